@@ -0,0 +1,132 @@
+package nthash
+
+// DefaultIUPACTable maps the standard IUPAC ambiguity codes (plus N) onto the
+// set of unambiguous bases they represent, for use with WithIUPACExpansion.
+var DefaultIUPACTable = map[byte][]byte{
+	'W': {'A', 'T'}, 'w': {'a', 't'},
+	'S': {'C', 'G'}, 's': {'c', 'g'},
+	'M': {'A', 'C'}, 'm': {'a', 'c'},
+	'K': {'G', 'T'}, 'k': {'g', 't'},
+	'R': {'A', 'G'}, 'r': {'a', 'g'},
+	'Y': {'C', 'T'}, 'y': {'c', 't'},
+	'B': {'C', 'G', 'T'}, 'b': {'c', 'g', 't'},
+	'D': {'A', 'G', 'T'}, 'd': {'a', 'g', 't'},
+	'H': {'A', 'C', 'T'}, 'h': {'a', 'c', 't'},
+	'V': {'A', 'C', 'G'}, 'v': {'a', 'c', 'g'},
+	'N': {'A', 'C', 'G', 'T'}, 'n': {'a', 'c', 'g', 't'},
+}
+
+// findAmbiguous scans the current window for a base with no entry in
+// seedTab (i.e. anything other than A/C/G/T, or U which seedTab maps to T,
+// upper or lower case) and
+// returns its absolute index into *nthi.seq. found is false if the window is
+// clean.
+func (nthi *NTHi) findAmbiguous() (pos uint, found bool) {
+	for i := nthi.currentIdx; i < nthi.currentIdx+nthi.k; i++ {
+		if seedTab[(*nthi.seq)[i]] == seedN {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// NextWithStatus returns the next ntHash value, behaving exactly like Next
+// unless SkipAmbiguous is set. With SkipAmbiguous set, any window containing
+// an ambiguous (non-ACGT) base is fast-forwarded past rather than hashed, and
+// skipped is returned true so that callers can distinguish a skipped
+// ambiguous region from genuine end-of-sequence (ok false, skipped false).
+//
+// SkipAmbiguous has no spaced-seed path: NextWithStatus panics if it is set
+// on a hasher built via NewSpacedHasher rather than silently mixing the
+// standard and spaced-seed algorithms.
+func (nthi *NTHi) NextWithStatus(canonical bool) (hash uint64, ok bool, skipped bool) {
+	if !nthi.SkipAmbiguous {
+		hash, ok = nthi.Next(canonical)
+		return hash, ok, false
+	}
+	if nthi.careIdx != nil {
+		panic("nthash: NextWithStatus: SkipAmbiguous is not supported on a spaced-seed hasher")
+	}
+
+	if nthi.currentIdx >= nthi.maxIdx {
+		poolNTHi.Put(nthi)
+		return 0, false, false
+	}
+
+	if pos, found := nthi.findAmbiguous(); found {
+		nthi.currentIdx = pos + 1
+		nthi.needsReseed = true
+		return 0, false, true
+	}
+
+	if nthi.needsReseed {
+		window := (*nthi.seq)[nthi.currentIdx : nthi.currentIdx+nthi.k]
+		nthi.fh = ntf64(window, 0, nthi.k)
+		nthi.rh = ntr64(window, 0, nthi.k)
+		nthi.needsReseed = false
+	} else {
+		prevBase := (*nthi.seq)[nthi.currentIdx-1]
+		endBase := (*nthi.seq)[nthi.currentIdx+nthi.k-1]
+		// alg 3. of ntHash paper
+		nthi.fh = roL(nthi.fh, 1)
+		nthi.fh ^= roL(seedTab[prevBase], nthi.k)
+		nthi.fh ^= seedTab[endBase]
+		nthi.rh = roR(nthi.rh, 1)
+		nthi.rh ^= roR(seedTab[prevBase&offset], 1)
+		nthi.rh ^= roL(seedTab[endBase&offset], nthi.k-1)
+	}
+	nthi.currentIdx++
+
+	if canonical {
+		return nthi.getCanonical(), true, false
+	}
+	return nthi.fh, true, false
+}
+
+// NextExpanded returns one hash per combination of bases in the current
+// window, expanding any base present in the table set via WithIUPACExpansion
+// into each of the bases it represents (e.g. W expands into A and T). A
+// window with no ambiguity codes from the table yields a single hash, same
+// as Next. ok is false once the sequence is exhausted.
+// canonical is set true to return canonical hashes, otherwise forward hashes
+// are returned.
+func (nthi *NTHi) NextExpanded(canonical bool) ([]uint64, bool) {
+	if nthi.currentIdx >= nthi.maxIdx {
+		poolNTHi.Put(nthi)
+		return nil, false
+	}
+
+	window := (*nthi.seq)[nthi.currentIdx : nthi.currentIdx+nthi.k]
+	combos := [][]byte{window}
+	if nthi.iupacTable != nil {
+		for i, base := range window {
+			options, ok := nthi.iupacTable[base]
+			if !ok {
+				continue
+			}
+			expanded := make([][]byte, 0, len(combos)*len(options))
+			for _, combo := range combos {
+				for _, replacement := range options {
+					next := append([]byte(nil), combo...)
+					next[i] = replacement
+					expanded = append(expanded, next)
+				}
+			}
+			combos = expanded
+		}
+	}
+
+	hashes := make([]uint64, len(combos))
+	for i, combo := range combos {
+		if canonical {
+			hashes[i] = ntc64(combo, 0, nthi.k)
+		} else {
+			hashes[i] = ntf64(combo, 0, nthi.k)
+		}
+	}
+
+	nthi.currentIdx++
+	nthi.needsReseed = true
+
+	return hashes, true
+}
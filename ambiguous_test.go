@@ -0,0 +1,100 @@
+package nthash
+
+import "testing"
+
+// test that SkipAmbiguous fast-forwards past an N and resumes correctly
+func TestNextWithStatusSkipAmbiguous(t *testing.T) {
+	seq := []byte("ACTGNACTGC")
+	nthi, err := NewHasher(&seq, 3, WithSkipAmbiguous(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sawSkip bool
+	count := 0
+	for {
+		_, ok, skipped := nthi.NextWithStatus(true)
+		if skipped {
+			sawSkip = true
+			continue
+		}
+		if !ok {
+			break
+		}
+		count++
+	}
+	if !sawSkip {
+		t.Fatal("expected at least one skipped window around the N")
+	}
+	// of the 8 windows in "ACTGNACTGC" (k=3), 3 overlap the N (idx 2,3,4) and
+	// are skipped, leaving 5 valid k-mers (idx 0,1,5,6,7)
+	if count != 5 {
+		t.Fatalf("expected 5 k-mers around the ambiguous region, got %d", count)
+	}
+}
+
+// test that without SkipAmbiguous, NextWithStatus behaves exactly like Next
+func TestNextWithStatusDefault(t *testing.T) {
+	nthi, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, ok, skipped := nthi.NextWithStatus(true)
+	if skipped {
+		t.Fatal("should not report skipped when SkipAmbiguous is unset")
+	}
+	if !ok || h != 0x9b1eda9a185413ce {
+		t.Fatal("NextWithStatus should match Next's first hash")
+	}
+}
+
+// test that NextWithStatus panics rather than silently mixing the standard
+// and spaced-seed algorithms when SkipAmbiguous is set on a spaced hasher
+func TestNextWithStatusSkipAmbiguousSpacedPanics(t *testing.T) {
+	spaced, err := NewSpacedHasher(&kmer2, "101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spaced.SkipAmbiguous = true
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected NextWithStatus to panic for SkipAmbiguous on a spaced-seed hasher")
+		}
+	}()
+	spaced.NextWithStatus(true)
+}
+
+// test that NextExpanded yields one hash per IUPAC expansion combination
+func TestNextExpanded(t *testing.T) {
+	seq := []byte("ACW") // W expands to {A, T}
+	nthi, err := NewHasher(&seq, 3, WithIUPACExpansion(DefaultIUPACTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes, ok := nthi.NextExpanded(true)
+	if !ok {
+		t.Fatal("expected a result")
+	}
+	if len(hashes) != 2 {
+		t.Fatalf("expected 2 hashes from a single W expansion, got %d", len(hashes))
+	}
+
+	seqA := []byte("ACA")
+	nthiA, err := NewHasher(&seqA, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantA, _ := nthiA.Next(true)
+
+	seqT := []byte("ACT")
+	nthiT, err := NewHasher(&seqT, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantT, _ := nthiT.Next(true)
+
+	if !((hashes[0] == wantA && hashes[1] == wantT) || (hashes[0] == wantT && hashes[1] == wantA)) {
+		t.Fatalf("expanded hashes %v did not match expected {%x, %x}", hashes, wantA, wantT)
+	}
+}
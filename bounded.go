@@ -0,0 +1,89 @@
+package nthash
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// BoundedU64 maps a 64-bit hash into the range [0, bound) using Lemire's fast
+// range reduction: the high 64 bits of the 128-bit product h*bound. This
+// avoids the division of the naive `h % bound`, but like the modulo it leaves
+// a small bias whenever bound does not divide 2^64 evenly (BoundedU64Unbiased
+// removes it). It is the building block for bucket/slot indices used by Bloom
+// filters, MinHash sketches, and count-min columns.
+//
+// bound must be greater than zero; BoundedU64 panics otherwise, since there
+// is no value in [0, 0) to return.
+func BoundedU64(h uint64, bound uint64) uint64 {
+	if bound == 0 {
+		panic("nthash: BoundedU64: bound must be greater than zero")
+	}
+	hi, _ := bits.Mul64(h, bound)
+	return hi
+}
+
+// BoundedU64Unbiased maps a 64-bit hash into the range [0, bound) exactly
+// uniformly, using Lemire's rejection-sampling variant: if the low 64 bits of
+// the product h*bound fall in the biased tail (below the threshold needed for
+// bound to divide 2^64 evenly), reroll is called for a fresh hash and the
+// reduction is retried.
+//
+// bound must be greater than zero; BoundedU64Unbiased panics otherwise, since
+// there is no value in [0, 0) to return.
+func BoundedU64Unbiased(h uint64, bound uint64, reroll func() uint64) uint64 {
+	if bound == 0 {
+		panic("nthash: BoundedU64Unbiased: bound must be greater than zero")
+	}
+	hi, lo := bits.Mul64(h, bound)
+	threshold := -bound % bound
+	for lo < threshold {
+		h = reroll()
+		hi, lo = bits.Mul64(h, bound)
+	}
+	return hi
+}
+
+// BoundedHash returns an iter.Seq that yields the ntHash values of a
+// sequence, each mapped into the range [0, bound) via BoundedU64, so callers
+// get near-uniformly distributed bucket indices directly without writing modulo
+// arithmetic themselves. It is built on top of Iter rather than a goroutine
+// and channel, so breaking out of the range loop early costs nothing, e.g.:
+//
+//	for idx := range nthi.BoundedHash(true, bound) {
+//	    ...
+//	}
+//
+// canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
+func (nthi *NTHi) BoundedHash(canonical bool, bound uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for h, _ := range nthi.Iter(canonical) {
+			if !yield(BoundedU64(h, bound)) {
+				return
+			}
+		}
+	}
+}
+
+// BoundedMultiHash returns an iter.Seq2 that yields the multi ntHash values
+// and k-mer offset of a sequence, each mapped into the range [0, bound) via
+// BoundedU64. It is built on top of MultiIter rather than a goroutine and
+// channel, so breaking out of the range loop early costs nothing. Like
+// MultiIter, it reuses a single internal buffer for the bounded slice across
+// iterations, so callers that only read the slice within the loop body avoid
+// a per-k-mer allocation. Callers that need to retain a slice past the
+// current iteration must copy it.
+// canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
+// numMultiHash sets the number of multi hashes to generate for each k-mer
+func (nthi *NTHi) BoundedMultiHash(canonical bool, numMultiHash uint, bound uint64) iter.Seq2[[]uint64, int] {
+	return func(yield func([]uint64, int) bool) {
+		bounded := make([]uint64, numMultiHash)
+		for hashes, offset := range nthi.MultiIter(canonical, numMultiHash) {
+			for i, h := range hashes {
+				bounded[i] = BoundedU64(h, bound)
+			}
+			if !yield(bounded, offset) {
+				return
+			}
+		}
+	}
+}
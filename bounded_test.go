@@ -0,0 +1,101 @@
+package nthash
+
+import "testing"
+
+// test that BoundedU64 always maps into [0, bound)
+func TestBoundedU64(t *testing.T) {
+	bound := uint64(17)
+	for _, h := range []uint64{0, 1, 123456789, ^uint64(0)} {
+		if got := BoundedU64(h, bound); got >= bound {
+			t.Fatalf("BoundedU64(%d, %d) = %d, want < %d", h, bound, got, bound)
+		}
+	}
+}
+
+// test that BoundedU64 panics with a clear message rather than silently
+// returning 0 for a zero bound
+func TestBoundedU64ZeroBound(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected BoundedU64 to panic for bound == 0")
+		}
+	}()
+	BoundedU64(123, 0)
+}
+
+// test that BoundedU64Unbiased panics with a clear message rather than an
+// unhelpful integer-divide-by-zero panic for a zero bound
+func TestBoundedU64UnbiasedZeroBound(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected BoundedU64Unbiased to panic for bound == 0")
+		}
+	}()
+	BoundedU64Unbiased(123, 0, func() uint64 { return 0 })
+}
+
+// test that BoundedU64Unbiased rerolls until it finds an unbiased mapping
+// and still always maps into [0, bound)
+func TestBoundedU64Unbiased(t *testing.T) {
+	bound := uint64(3)
+	// 2^64 % 3 == 1, so only h == 0 (lo == 0) falls in the biased tail;
+	// starting the seed at 0 makes the first call reroll
+	seed := uint64(0)
+	rerolls := 0
+	reroll := func() uint64 {
+		rerolls++
+		seed++
+		return seed
+	}
+	for i := 0; i < 100; i++ {
+		if got := BoundedU64Unbiased(seed, bound, reroll); got >= bound {
+			t.Fatalf("BoundedU64Unbiased(...) = %d, want < %d", got, bound)
+		}
+	}
+	if rerolls == 0 {
+		t.Fatal("expected BoundedU64Unbiased to reroll for h == 0")
+	}
+}
+
+// test the BoundedHash convenience method
+func TestBoundedHash(t *testing.T) {
+	nthi, err := NewHasher(&sequence, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound := uint64(64)
+	count := 0
+	for idx := range nthi.BoundedHash(true, bound) {
+		if idx >= bound {
+			t.Fatalf("bounded hash %d >= bound %d", idx, bound)
+		}
+		count++
+	}
+	if count == 0 {
+		t.Fatal("expected at least one bounded hash")
+	}
+}
+
+// test the BoundedMultiHash convenience method
+func TestBoundedMultiHash(t *testing.T) {
+	nthi, err := NewHasher(&sequence, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound := uint64(64)
+	count := 0
+	for indexes, _ := range nthi.BoundedMultiHash(true, 3, bound) {
+		if len(indexes) != 3 {
+			t.Fatal("expected 3 bounded hashes per k-mer")
+		}
+		for _, idx := range indexes {
+			if idx >= bound {
+				t.Fatalf("bounded hash %d >= bound %d", idx, bound)
+			}
+		}
+		count++
+	}
+	if count == 0 {
+		t.Fatal("expected at least one bounded multi-hash")
+	}
+}
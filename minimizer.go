@@ -0,0 +1,91 @@
+package nthash
+
+// minimizerEntry is a candidate held in the Minimizer's monotonic deque.
+type minimizerEntry struct {
+	hash uint64
+	pos  uint
+}
+
+// Minimizer computes the (w,k)-minimizer scheme over the k-mers produced by
+// an NTHi: the minimum canonical hash within each sliding window of w
+// consecutive k-mers, the standard scheme used throughout bioinformatics
+// indexes to subsample a sequence's k-mers for sketching and indexing.
+type Minimizer struct {
+	nthi      *NTHi
+	w         uint
+	canonical bool
+
+	// deque holds, front to back, hashes strictly increasing and positions
+	// strictly increasing: the standard monotonic deque for a sliding window
+	// minimum
+	deque []minimizerEntry
+
+	pos        uint
+	hasLast    bool
+	lastMinPos uint
+	done       bool
+}
+
+// NewMinimizer is the constructor function for Minimizer. nthi is the ntHash
+// iterator to consume k-mers from, w is the number of consecutive k-mers in
+// each window, and canonical is set true to minimize over canonical hashes,
+// otherwise the forward hashes are used.
+//
+// w must be greater than zero: a window of size zero can never fill, so a
+// Minimizer constructed with w == 0 is valid but Next always reports
+// ok == false rather than panicking.
+func NewMinimizer(nthi *NTHi, w uint, canonical bool) *Minimizer {
+	return &Minimizer{
+		nthi:      nthi,
+		w:         w,
+		canonical: canonical,
+		deque:     make([]minimizerEntry, 0, w),
+	}
+}
+
+// Next returns the next distinct minimizer: its hash, and the k-mer offset
+// (position) at which it occurs. Consecutive windows sharing the same
+// minimizer are deduplicated, so Next only returns when the minimizer
+// changes. ok is false once the underlying NTHi is exhausted, or immediately
+// and permanently if the Minimizer was constructed with w == 0.
+func (m *Minimizer) Next() (uint64, uint, bool) {
+	if m.done || m.w == 0 {
+		m.done = true
+		return 0, 0, false
+	}
+
+	for {
+		hash, ok := m.nthi.Next(m.canonical)
+		if !ok {
+			m.done = true
+			return 0, 0, false
+		}
+
+		pos := m.pos
+		m.pos++
+
+		// pop back entries that can never be the minimum again
+		for len(m.deque) > 0 && m.deque[len(m.deque)-1].hash >= hash {
+			m.deque = m.deque[:len(m.deque)-1]
+		}
+		m.deque = append(m.deque, minimizerEntry{hash: hash, pos: pos})
+
+		// pop the front if it has fallen outside the window
+		for len(m.deque) > 0 && m.deque[0].pos+m.w <= pos {
+			m.deque = m.deque[1:]
+		}
+
+		// the window isn't full until we've seen w k-mers
+		if pos+1 < m.w {
+			continue
+		}
+
+		front := m.deque[0]
+		if m.hasLast && m.lastMinPos == front.pos {
+			continue
+		}
+		m.hasLast = true
+		m.lastMinPos = front.pos
+		return front.hash, front.pos, true
+	}
+}
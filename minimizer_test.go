@@ -0,0 +1,84 @@
+package nthash
+
+import "testing"
+
+// test that a Minimizer constructed with w == 0 never panics and always
+// reports ok == false, rather than paniking with an out-of-range index
+func TestMinimizerZeroWindow(t *testing.T) {
+	nthi, err := NewHasher(&sequence, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMinimizer(nthi, 0, true)
+	if _, _, ok := m.Next(); ok {
+		t.Fatal("expected ok == false for a w == 0 Minimizer")
+	}
+	// and again, to confirm it stays false rather than panicking on a second call
+	if _, _, ok := m.Next(); ok {
+		t.Fatal("expected ok == false to persist for a w == 0 Minimizer")
+	}
+}
+
+// test the minimizer iterator against a brute-force sliding window minimum
+func TestMinimizer(t *testing.T) {
+	k := uint(5)
+	w := uint(3)
+
+	nthi, err := NewHasher(&sequence, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hashes []uint64
+	for h, ok := nthi.Next(true); ok; h, ok = nthi.Next(true) {
+		hashes = append(hashes, h)
+	}
+
+	nthi2, err := NewHasher(&sequence, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMinimizer(nthi2, w, true)
+
+	var gotPos []uint
+	var gotHash []uint64
+	for {
+		h, pos, ok := m.Next()
+		if !ok {
+			break
+		}
+		gotHash = append(gotHash, h)
+		gotPos = append(gotPos, pos)
+	}
+
+	// brute-force the expected (deduplicated) minimizers
+	var wantPos []uint
+	var wantHash []uint64
+	lastPos := uint(0)
+	hasLast := false
+	for start := 0; start+int(w) <= len(hashes); start++ {
+		minPos := uint(start)
+		minHash := hashes[start]
+		for i := start + 1; i < start+int(w); i++ {
+			if hashes[i] < minHash {
+				minHash = hashes[i]
+				minPos = uint(i)
+			}
+		}
+		if hasLast && lastPos == minPos {
+			continue
+		}
+		hasLast = true
+		lastPos = minPos
+		wantPos = append(wantPos, minPos)
+		wantHash = append(wantHash, minHash)
+	}
+
+	if len(gotPos) != len(wantPos) {
+		t.Fatalf("expected %d minimizers, got %d", len(wantPos), len(gotPos))
+	}
+	for i := range wantPos {
+		if gotPos[i] != wantPos[i] || gotHash[i] != wantHash[i] {
+			t.Fatalf("minimizer %d: got (hash=%x, pos=%d), want (hash=%x, pos=%d)", i, gotHash[i], gotPos[i], wantHash[i], wantPos[i])
+		}
+	}
+}
@@ -6,6 +6,7 @@ package nthash
 
 import (
 	"fmt"
+	"iter"
 	"math"
 	"sync"
 )
@@ -86,6 +87,45 @@ type NTHi struct {
 	rh         uint64  // the current reverse hash value
 	currentIdx uint    // the current index position in the sequence being hashed
 	maxIdx     uint    // the maximum index position to hash up to
+
+	// careIdx and mirrorIdx are set by NewSpacedHasher to switch Next into
+	// spaced-seed mode; they are nil for a standard hasher
+	careIdx   []uint
+	mirrorIdx []uint
+
+	// SkipAmbiguous, when true, causes NextWithStatus to fast-forward past any
+	// window containing an ambiguous (non-ACGT) base rather than silently
+	// hashing it as if it were seedN
+	SkipAmbiguous bool
+
+	// needsReseed tracks whether fh/rh must be recomputed from scratch (via
+	// ntf64/ntr64) rather than rolled, which is the case for the first window
+	// and for the first window after a SkipAmbiguous fast-forward
+	needsReseed bool
+
+	// iupacTable, when set by WithIUPACExpansion, causes NextExpanded to expand
+	// any base it contains a key for into every hash resulting from each of its
+	// possible bases
+	iupacTable map[byte][]byte
+}
+
+// HasherOpt configures an NTHi at construction time.
+type HasherOpt func(*NTHi)
+
+// WithSkipAmbiguous sets SkipAmbiguous on the constructed hasher.
+func WithSkipAmbiguous(skip bool) HasherOpt {
+	return func(nthi *NTHi) {
+		nthi.SkipAmbiguous = skip
+	}
+}
+
+// WithIUPACExpansion sets the table of IUPAC ambiguity codes that NextExpanded
+// will expand into multiple hashes per position. See DefaultIUPACTable for a
+// ready-made table covering the standard IUPAC nucleotide codes.
+func WithIUPACExpansion(table map[byte][]byte) HasherOpt {
+	return func(nthi *NTHi) {
+		nthi.iupacTable = table
+	}
 }
 
 // use object pool to reducing GC load for computation of huge number of sequences.
@@ -96,7 +136,7 @@ var poolNTHi = &sync.Pool{New: func() interface{} {
 // NewHasher is the constructor function for the ntHash iterator
 // seq is a pointer to the sequence being hashed
 // k is the k-mer size to use
-func NewHasher(seq *[]byte, k uint) (*NTHi, error) {
+func NewHasher(seq *[]byte, k uint, opts ...HasherOpt) (*NTHi, error) {
 	seqLen := uint(len(*seq))
 	if k > seqLen {
 		return nil, fmt.Errorf("k size is greater than sequence length (%d vs %d)", k, seqLen)
@@ -114,6 +154,15 @@ func NewHasher(seq *[]byte, k uint) (*NTHi, error) {
 	nthi.rh = rh
 	nthi.currentIdx = 0
 	nthi.maxIdx = seqLen - (k - 1)
+	nthi.careIdx = nil
+	nthi.mirrorIdx = nil
+	nthi.SkipAmbiguous = false
+	nthi.needsReseed = true
+	nthi.iupacTable = nil
+
+	for _, opt := range opts {
+		opt(nthi)
+	}
 
 	return nthi, nil
 }
@@ -127,6 +176,20 @@ func (nthi *NTHi) Next(canonical bool) (uint64, bool) {
 		return 0, false
 	}
 
+	// a spaced-seed hasher has no rolling shortcut, so recompute from the
+	// care positions on every call instead of rolling fh/rh
+	if nthi.careIdx != nil {
+		nthi.fh = spacedForward(*nthi.seq, nthi.currentIdx, nthi.k, nthi.careIdx)
+		if canonical {
+			nthi.rh = spacedReverse(*nthi.seq, nthi.currentIdx, nthi.k, nthi.mirrorIdx)
+		}
+		nthi.currentIdx++
+		if canonical {
+			return nthi.getCanonical(), true
+		}
+		return nthi.fh, true
+	}
+
 	// roll the hash if index>0
 	if nthi.currentIdx != 0 {
 		prevBase := (*nthi.seq)[nthi.currentIdx-1]
@@ -147,50 +210,107 @@ func (nthi *NTHi) Next(canonical bool) (uint64, bool) {
 	return nthi.fh, true
 }
 
-// Hash returns a channel to range over the canonical ntHash values of a sequence
+// Iter returns an iter.Seq2 that yields the ntHash value and k-mer offset for each
+// k-mer in the sequence. It replaces Hash with a channel-free, allocation-free
+// range-over-func loop, e.g.:
+//
+//	for hash, offset := range nthi.Iter(true) {
+//	    ...
+//	}
+//
 // canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
-func (nthi *NTHi) Hash(canonical bool) <-chan uint64 {
-	hashChan := make(chan uint64, bufferSize)
-	go func() {
-		defer close(hashChan)
-
-		// start the rolling hash
+func (nthi *NTHi) Iter(canonical bool) iter.Seq2[uint64, int] {
+	return func(yield func(uint64, int) bool) {
 		for {
+			// delegate to NextWithStatus so that a spaced-seed hasher (built
+			// via NewSpacedHasher) is hashed via its careIdx-aware path, and
+			// a SkipAmbiguous hasher fast-forwards past ambiguous windows
+			// instead of hashing through them
+			hv, ok, skipped := nthi.NextWithStatus(canonical)
+			if skipped {
+				continue
+			}
+			if !ok {
+				return
+			}
+			offset := int(nthi.currentIdx) - 1
 
-			// check that rolling can continue
-			if nthi.currentIdx >= nthi.maxIdx {
-				poolNTHi.Put(nthi)
+			if !yield(hv, offset) {
+				return
+			}
+		}
+	}
+}
+
+// MultiIter returns an iter.Seq2 that yields the multi ntHash values and k-mer offset
+// for each k-mer in the sequence. It replaces MultiHash with a channel-free,
+// range-over-func loop that reuses a single internal buffer for the multi-hash
+// slice across iterations, so callers that only read the slice within the loop
+// body avoid a per-k-mer allocation. Callers that need to retain a slice past
+// the current iteration must copy it.
+// canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
+// numMultiHash sets the number of multi hashes to generate for each k-mer
+func (nthi *NTHi) MultiIter(canonical bool, numMultiHash uint) iter.Seq2[[]uint64, int] {
+	multiHashes := make([]uint64, numMultiHash)
+	return func(yield func([]uint64, int) bool) {
+		for {
+			// delegate to NextWithStatus so that a spaced-seed hasher (built
+			// via NewSpacedHasher) is hashed via its careIdx-aware path, and
+			// a SkipAmbiguous hasher fast-forwards past ambiguous windows
+			// instead of hashing through them
+			hv, ok, skipped := nthi.NextWithStatus(canonical)
+			if skipped {
+				continue
+			}
+			if !ok {
 				return
 			}
+			offset := int(nthi.currentIdx) - 1
 
-			// start the hashing
-			if nthi.currentIdx != 0 {
-				prevBase := (*nthi.seq)[nthi.currentIdx-1]
-				endBase := (*nthi.seq)[nthi.currentIdx+nthi.k-1]
-				// alg 3. of ntHash paper
-				nthi.fh = roL(nthi.fh, 1)
-				nthi.fh ^= roL(seedTab[prevBase], nthi.k)
-				nthi.fh ^= seedTab[endBase]
-				nthi.rh = roR(nthi.rh, 1)
-				nthi.rh ^= roR(seedTab[prevBase&offset], 1)
-				nthi.rh ^= roL(seedTab[endBase&offset], nthi.k-1)
+			multiHashes[0] = hv
+			for i := uint64(1); i < uint64(numMultiHash); i++ {
+				tVal := multiHashes[0] * (i ^ uint64(nthi.k)*multiSeed)
+				tVal ^= tVal >> multiShift
+				multiHashes[i] = tVal
 			}
 
-			// calculate and return the canonical ntHash if requested
-			if canonical {
-				hashChan <- nthi.getCanonical()
-			} else {
-				hashChan <- nthi.fh
+			if !yield(multiHashes, offset) {
+				return
 			}
+		}
+	}
+}
 
-			// increment the index
-			nthi.currentIdx++
+// Hash returns a channel to range over the canonical ntHash values of a sequence
+//
+// Deprecated: use Iter instead, which avoids the goroutine and channel overhead
+// of Hash and is the preferred API going forward.
+//
+// canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
+func (nthi *NTHi) Hash(canonical bool) <-chan uint64 {
+	hashChan := make(chan uint64, bufferSize)
+	go func() {
+		defer close(hashChan)
+
+		// delegate to Next so that a spaced-seed hasher (built via
+		// NewSpacedHasher) is hashed via its careIdx-aware path instead of
+		// the standard rolling hash
+		for {
+			hv, ok := nthi.Next(canonical)
+			if !ok {
+				return
+			}
+			hashChan <- hv
 		}
 	}()
 	return hashChan
 }
 
 // MultiHash returns a channel to range over the canonical multi ntHash values of a sequence
+//
+// Deprecated: use MultiIter instead, which avoids the goroutine and channel
+// overhead of MultiHash and is the preferred API going forward.
+//
 // canonical is set true to return the canonical k-mers, otherwise the forward hashes are returned
 // numMultiHash sets the number of multi hashes to generate for each k-mer
 func (nthi *NTHi) MultiHash(canonical bool, numMultiHash uint) <-chan []uint64 {
@@ -198,36 +318,18 @@ func (nthi *NTHi) MultiHash(canonical bool, numMultiHash uint) <-chan []uint64 {
 	go func() {
 		defer close(hashChan)
 
-		// start the rolling hash
+		// delegate to Next so that a spaced-seed hasher (built via
+		// NewSpacedHasher) is hashed via its careIdx-aware path instead of
+		// the standard rolling hash
 		for {
-
-			// check that rolling can continue
-			if nthi.currentIdx >= nthi.maxIdx {
-				poolNTHi.Put(nthi)
+			hv, ok := nthi.Next(canonical)
+			if !ok {
 				return
 			}
 
-			// start the hashing
-			if nthi.currentIdx != 0 {
-				prevBase := (*nthi.seq)[nthi.currentIdx-1]
-				endBase := (*nthi.seq)[nthi.currentIdx+nthi.k-1]
-				// alg 3. of ntHash paper
-				nthi.fh = roL(nthi.fh, 1)
-				nthi.fh ^= roL(seedTab[prevBase], nthi.k)
-				nthi.fh ^= seedTab[endBase]
-				nthi.rh = roR(nthi.rh, 1)
-				nthi.rh ^= roR(seedTab[prevBase&offset], 1)
-				nthi.rh ^= roL(seedTab[endBase&offset], nthi.k-1)
-			}
-
 			// set up the return slice
 			multiHashes := make([]uint64, numMultiHash)
-			if canonical {
-				multiHashes[0] = nthi.getCanonical()
-			} else {
-				multiHashes[0] = nthi.fh
-			}
-
+			multiHashes[0] = hv
 			for i := uint64(1); i < uint64(numMultiHash); i++ {
 				tVal := multiHashes[0] * (i ^ uint64(nthi.k)*multiSeed)
 				tVal ^= tVal >> multiShift
@@ -236,9 +338,6 @@ func (nthi *NTHi) MultiHash(canonical bool, numMultiHash uint) <-chan []uint64 {
 
 			// send the multihashes for this k-mer
 			hashChan <- multiHashes
-
-			// increment the index
-			nthi.currentIdx++
 		}
 	}()
 	return hashChan
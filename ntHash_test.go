@@ -168,6 +168,73 @@ func TestMultiHash(t *testing.T) {
 	}
 }
 
+// test the ntHash iterator Iter method
+func TestIter(t *testing.T) {
+	nthi, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal()
+	}
+	counter := 0
+	// use the canonical switch
+	for hash, offset := range nthi.Iter(true) {
+		t.Log(hash, offset)
+		counter++
+		switch counter {
+		case 1:
+			if hash != 0x9b1eda9a185413ce || offset != 0 {
+				t.Fatal()
+			}
+		case 2:
+			if hash != 0x9f6acfa2235b86fc || offset != 1 {
+				t.Fatal()
+			}
+		case 3:
+			if hash != 0xd4a29bf149877c5c || offset != 2 {
+				t.Fatal()
+			}
+		default:
+			t.Fatal("unexpected output from nthi")
+		}
+	}
+	if counter != 3 {
+		t.Fatal("wrong iteration")
+	}
+}
+
+// test the ntHash iterator MultiIter method
+func TestMultiIter(t *testing.T) {
+	nthi, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal()
+	}
+	counter := 0
+
+	// use the canonical switch and 3 multihashes
+	for hashes, offset := range nthi.MultiIter(true, 3) {
+		t.Log(hashes, offset)
+		counter++
+		switch counter {
+		case 1:
+			if hashes[0] != 0x9b1eda9a185413ce || offset != 0 {
+				t.Fatal()
+			}
+		case 2:
+			if hashes[0] != 0x9f6acfa2235b86fc || offset != 1 {
+				t.Fatal()
+			}
+		case 3:
+			if hashes[0] != 0xd4a29bf149877c5c || offset != 2 {
+				t.Fatal()
+			}
+		default:
+			t.Fatal("unexpected output from nthi")
+		}
+	}
+	if counter != 3 {
+		t.Fatal("wrong iteration")
+	}
+}
+
 // run benchmarks of ntHash
 func BenchmarkHash(b *testing.B) {
 	// run the ntHash iterator b.N times
@@ -192,3 +259,50 @@ func BenchmarkCanonicalHash(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkIter demonstrates the speedup of Iter over the channel-based Hash
+func BenchmarkIter(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		nthi, err := NewHasher(&sequence, 7)
+		if err != nil {
+			b.Fatal()
+		}
+		for range nthi.Iter(false) {
+		}
+	}
+}
+
+func BenchmarkCanonicalIter(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		nthi, err := NewHasher(&sequence, 7)
+		if err != nil {
+			b.Fatal()
+		}
+		for range nthi.Iter(true) {
+		}
+	}
+}
+
+// BenchmarkMultiHash demonstrates the cost of the channel-based MultiHash
+func BenchmarkMultiHash(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		nthi, err := NewHasher(&sequence, 7)
+		if err != nil {
+			b.Fatal()
+		}
+		for range nthi.MultiHash(true, 3) {
+		}
+	}
+}
+
+// BenchmarkMultiIter demonstrates the speedup of MultiIter over MultiHash
+func BenchmarkMultiIter(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		nthi, err := NewHasher(&sequence, 7)
+		if err != nil {
+			b.Fatal()
+		}
+		for range nthi.MultiIter(true, 3) {
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package nthash
+
+import (
+	"fmt"
+	"iter"
+)
+
+// parseSeed validates a spaced-seed string and returns its length (the k-mer
+// size it applies to) and the list of "care" positions within it, i.e. the
+// indices where seed is '1'.
+func parseSeed(seed string) (uint, []uint, error) {
+	if len(seed) == 0 {
+		return 0, nil, fmt.Errorf("seed must not be empty")
+	}
+	careIdx := make([]uint, 0, len(seed))
+	for i := 0; i < len(seed); i++ {
+		switch seed[i] {
+		case '1':
+			careIdx = append(careIdx, uint(i))
+		case '0':
+			// don't-care position
+		default:
+			return 0, nil, fmt.Errorf("seed must only contain '0' and '1' characters, got %q at position %d", seed[i], i)
+		}
+	}
+	if len(careIdx) == 0 {
+		return 0, nil, fmt.Errorf("seed must contain at least one care ('1') position")
+	}
+	return uint(len(seed)), careIdx, nil
+}
+
+// mirrorCareIdx returns the care positions mirrored about the centre of a
+// k-mer of size k, i.e. position i maps to k-1-i. A symmetric (palindromic)
+// seed mirrors onto itself, which is what makes its forward and
+// reverse-complement hashes consistent; an asymmetric seed is auto-mirrored
+// here so NewSpacedHasher still produces a usable, if less rigorously
+// justified, canonical hash.
+func mirrorCareIdx(careIdx []uint, k uint) []uint {
+	mirrored := make([]uint, len(careIdx))
+	for i, ci := range careIdx {
+		mirrored[i] = k - 1 - ci
+	}
+	return mirrored
+}
+
+// spacedForward computes the forward spaced-seed hash for the k-mer at pos,
+// i.e. the XOR-sum over care positions i of roL(seedTab[seq[pos+i]], k-1-i).
+func spacedForward(seq []byte, pos, k uint, careIdx []uint) uint64 {
+	var hv uint64
+	for _, ci := range careIdx {
+		hv ^= roL(seedTab[seq[pos+ci]], k-1-ci)
+	}
+	return hv
+}
+
+// spacedReverse computes the reverse-complement spaced-seed hash for the
+// k-mer at pos, using the mirrored care positions returned by mirrorCareIdx.
+func spacedReverse(seq []byte, pos, k uint, mirrorIdx []uint) uint64 {
+	var hv uint64
+	for _, j := range mirrorIdx {
+		hv ^= roL(seedTab[seq[pos+j]&offset], j)
+	}
+	return hv
+}
+
+// NewSpacedHasher is the constructor function for a spaced-seed ntHash
+// iterator. seq is a pointer to the sequence being hashed, and seed is a
+// string of '1' (care) and '0' (don't-care) characters, e.g. "11011011011",
+// whose length sets the k-mer size. Only the bases at care positions
+// contribute to the hash, which enables sensitive inexact k-mer matching.
+//
+// Unlike a standard hasher, a spaced-seed hasher has no rolling shortcut: the
+// hash is recomputed from the care positions at every step, though only over
+// those positions rather than the full k-mer. For the canonical hash to be
+// meaningful, seed should be a palindrome (read the same forwards and
+// backwards); an asymmetric seed is auto-mirrored for the reverse-complement
+// strand, but the resulting canonical hash should then be treated with
+// caution.
+func NewSpacedHasher(seq *[]byte, seed string) (*NTHi, error) {
+	k, careIdx, err := parseSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	seqLen := uint(len(*seq))
+	if k > seqLen {
+		return nil, fmt.Errorf("k size is greater than sequence length (%d vs %d)", k, seqLen)
+	}
+	if k > maxK {
+		return nil, fmt.Errorf("k size is greater than the maximum allowed k size (%d vs %d)", k, maxK)
+	}
+
+	nthi := poolNTHi.Get().(*NTHi)
+	nthi.seq = seq
+	nthi.k = k
+	nthi.currentIdx = 0
+	nthi.maxIdx = seqLen - (k - 1)
+	nthi.careIdx = careIdx
+	nthi.mirrorIdx = mirrorCareIdx(careIdx, k)
+	nthi.SkipAmbiguous = false
+	nthi.needsReseed = true
+	nthi.iupacTable = nil
+
+	return nthi, nil
+}
+
+// MultiSpacedHasher is the multi-seed spaced-seed ntHash iterator returned by
+// NewMultiSpacedHasher. It is a distinct type from NTHi, rather than another
+// mode of it, because its per-position result is a slice of hashes (one per
+// seed) instead of NTHi's single uint64: the two can't share Next's return
+// type, so they don't share a struct or a sync.Pool either. This keeps
+// Next/Iter/MultiIter and MultiSpacedHasher's own Next/Iter from ever being
+// called on a hasher built for the other mode.
+type MultiSpacedHasher struct {
+	seq         *[]byte  // the sequence being hashed
+	k           uint     // the k-mer size
+	seeds       [][]uint // the care positions for each seed
+	seedMirrors [][]uint // the mirrored care positions for each seed
+	currentIdx  uint     // the current index position in the sequence being hashed
+	maxIdx      uint     // the maximum index position to hash up to
+}
+
+// NewMultiSpacedHasher is the constructor function for a multi-seed
+// spaced-seed ntHash iterator. seq is a pointer to the sequence being hashed,
+// and seeds is a slice of seed strings (see NewSpacedHasher), all of which
+// must be the same length. Use Next or Iter to retrieve, in a single pass
+// over seq, one hash per seed at each position.
+func NewMultiSpacedHasher(seq *[]byte, seeds []string) (*MultiSpacedHasher, error) {
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("must supply at least one seed")
+	}
+	k, careIdx0, err := parseSeed(seeds[0])
+	if err != nil {
+		return nil, fmt.Errorf("seed 0: %w", err)
+	}
+
+	allCare := make([][]uint, len(seeds))
+	allMirror := make([][]uint, len(seeds))
+	allCare[0] = careIdx0
+	allMirror[0] = mirrorCareIdx(careIdx0, k)
+	for i := 1; i < len(seeds); i++ {
+		ki, careIdx, err := parseSeed(seeds[i])
+		if err != nil {
+			return nil, fmt.Errorf("seed %d: %w", i, err)
+		}
+		if ki != k {
+			return nil, fmt.Errorf("all seeds must be the same length (seed 0 is %d, seed %d is %d)", k, i, ki)
+		}
+		allCare[i] = careIdx
+		allMirror[i] = mirrorCareIdx(careIdx, k)
+	}
+
+	seqLen := uint(len(*seq))
+	if k > seqLen {
+		return nil, fmt.Errorf("k size is greater than sequence length (%d vs %d)", k, seqLen)
+	}
+	if k > maxK {
+		return nil, fmt.Errorf("k size is greater than the maximum allowed k size (%d vs %d)", k, maxK)
+	}
+
+	return &MultiSpacedHasher{
+		seq:         seq,
+		k:           k,
+		seeds:       allCare,
+		seedMirrors: allMirror,
+		currentIdx:  0,
+		maxIdx:      seqLen - (k - 1),
+	}, nil
+}
+
+// Next returns the next set of per-seed spaced-seed hashes, one hash per seed
+// in the order they were supplied to NewMultiSpacedHasher.
+// canonical is set true to return the canonical hash for each seed, otherwise
+// the forward hash is returned.
+func (msh *MultiSpacedHasher) Next(canonical bool) ([]uint64, bool) {
+	if msh.currentIdx >= msh.maxIdx {
+		return nil, false
+	}
+
+	hashes := make([]uint64, len(msh.seeds))
+	for i := range msh.seeds {
+		fh := spacedForward(*msh.seq, msh.currentIdx, msh.k, msh.seeds[i])
+		if canonical {
+			rh := spacedReverse(*msh.seq, msh.currentIdx, msh.k, msh.seedMirrors[i])
+			if rh < fh {
+				hashes[i] = rh
+				continue
+			}
+		}
+		hashes[i] = fh
+	}
+	msh.currentIdx++
+
+	return hashes, true
+}
+
+// Iter returns an iter.Seq2 that yields the per-seed spaced-seed hashes and
+// k-mer offset for each k-mer in the sequence, e.g.:
+//
+//	for hashes, offset := range msh.Iter(true) {
+//	    ...
+//	}
+//
+// canonical is set true to return the canonical hash for each seed, otherwise
+// the forward hash is returned.
+func (msh *MultiSpacedHasher) Iter(canonical bool) iter.Seq2[[]uint64, int] {
+	return func(yield func([]uint64, int) bool) {
+		for {
+			hashes, ok := msh.Next(canonical)
+			if !ok {
+				return
+			}
+			offset := int(msh.currentIdx) - 1
+
+			if !yield(hashes, offset) {
+				return
+			}
+		}
+	}
+}
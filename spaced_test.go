@@ -0,0 +1,216 @@
+package nthash
+
+import "testing"
+
+// test the spaced-seed hasher constructor
+func TestNewSpacedHasher(t *testing.T) {
+	if _, err := NewSpacedHasher(&kmer2, "102"); err == nil {
+		t.Fatal("should trigger bad seed character error")
+	}
+	if _, err := NewSpacedHasher(&kmer2, "111111111111111111111111111111111"); err == nil {
+		t.Fatal("should trigger k > seq length error")
+	}
+	if _, err := NewSpacedHasher(&kmer2, "101"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// test that an all-care seed matches the standard ntHash for each k-mer
+func TestSpacedHasherAllCare(t *testing.T) {
+	spaced, err := NewSpacedHasher(&kmer2, "111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	standard, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		sh, sok := spaced.Next(true)
+		nh, nok := standard.Next(true)
+		if sok != nok {
+			t.Fatal("iterators disagree on when they are exhausted")
+		}
+		if !sok {
+			break
+		}
+		if sh != nh {
+			t.Fatalf("spaced hash %x did not match standard hash %x for an all-care seed", sh, nh)
+		}
+	}
+}
+
+// test that Iter on a spaced hasher uses the spaced-seed path, not the
+// standard rolling hash, by comparing against Next on an identically
+// constructed hasher
+func TestSpacedHasherIter(t *testing.T) {
+	seq := []byte("ACGTACGTAC")
+	seed := "1010"
+
+	viaNext, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantFwd, wantCan []uint64
+	for {
+		h, ok := viaNext.Next(false)
+		if !ok {
+			break
+		}
+		wantFwd = append(wantFwd, h)
+	}
+
+	viaNextCanonical, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		h, ok := viaNextCanonical.Next(true)
+		if !ok {
+			break
+		}
+		wantCan = append(wantCan, h)
+	}
+
+	viaIter, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotFwd []uint64
+	for h, _ := range viaIter.Iter(false) {
+		gotFwd = append(gotFwd, h)
+	}
+	if len(gotFwd) != len(wantFwd) {
+		t.Fatalf("Iter yielded %d hashes, Next yielded %d", len(gotFwd), len(wantFwd))
+	}
+	for i := range wantFwd {
+		if gotFwd[i] != wantFwd[i] {
+			t.Fatalf("Iter hash %d = %x, want %x (spaced-seed forward hash from Next)", i, gotFwd[i], wantFwd[i])
+		}
+	}
+
+	viaIterCanonical, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotCan []uint64
+	for h, _ := range viaIterCanonical.Iter(true) {
+		gotCan = append(gotCan, h)
+	}
+	for i := range wantCan {
+		if gotCan[i] != wantCan[i] {
+			t.Fatalf("Iter canonical hash %d = %x, want %x (spaced-seed canonical hash from Next)", i, gotCan[i], wantCan[i])
+		}
+	}
+}
+
+// test that NewSpacedHasher resets SkipAmbiguous/iupacTable on a pooled NTHi
+// that a previous standard hasher left set, so a spaced hasher never
+// inherits stale ambiguous-handling state from the sync.Pool
+func TestNewSpacedHasherResetsPooledState(t *testing.T) {
+	seq := []byte("ACGTACGTAC")
+	seed := "1010"
+
+	// drain a standard hasher with SkipAmbiguous/IUPAC expansion enabled so
+	// its underlying NTHi is returned to poolNTHi with those fields set
+	primed, err := NewHasher(&seq, 4, WithSkipAmbiguous(true), WithIUPACExpansion(DefaultIUPACTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, ok, _ := primed.NextWithStatus(true); !ok {
+			break
+		}
+	}
+
+	spaced, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NewSpacedHasher(&seq, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		got, gok, _ := spaced.NextWithStatus(true)
+		wantHash, wok := want.Next(true)
+		if gok != wok {
+			t.Fatal("iterators disagree on when they are exhausted")
+		}
+		if !gok {
+			break
+		}
+		if got != wantHash {
+			t.Fatalf("spaced hash %x did not match expected spaced hash %x; stale pooled state leaked in", got, wantHash)
+		}
+	}
+}
+
+// test the multi-seed spaced hasher
+func TestNewMultiSpacedHasher(t *testing.T) {
+	if _, err := NewMultiSpacedHasher(&kmer2, nil); err == nil {
+		t.Fatal("should trigger no seeds error")
+	}
+	if _, err := NewMultiSpacedHasher(&kmer2, []string{"111", "11"}); err == nil {
+		t.Fatal("should trigger mismatched seed length error")
+	}
+
+	multi, err := NewMultiSpacedHasher(&kmer2, []string{"111", "101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := 0
+	for {
+		hashes, ok := multi.Next(true)
+		if !ok {
+			break
+		}
+		if len(hashes) != 2 {
+			t.Fatal("expected one hash per seed")
+		}
+		counter++
+	}
+	if counter != 3 {
+		t.Fatalf("expected 3 k-mers from a 5-base sequence with a 3-base seed, got %d", counter)
+	}
+}
+
+// test that MultiSpacedHasher.Iter agrees with Next and stops the range loop
+// when exhausted
+func TestMultiSpacedHasherIter(t *testing.T) {
+	want, err := NewMultiSpacedHasher(&kmer2, []string{"111", "101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantHashes [][]uint64
+	for {
+		hashes, ok := want.Next(true)
+		if !ok {
+			break
+		}
+		wantHashes = append(wantHashes, hashes)
+	}
+
+	got, err := NewMultiSpacedHasher(&kmer2, []string{"111", "101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := 0
+	for hashes, offset := range got.Iter(true) {
+		if offset != i {
+			t.Fatalf("offset %d, want %d", offset, i)
+		}
+		if len(hashes) != len(wantHashes[i]) {
+			t.Fatalf("hash count %d, want %d", len(hashes), len(wantHashes[i]))
+		}
+		for j := range hashes {
+			if hashes[j] != wantHashes[i][j] {
+				t.Fatalf("hash %d at offset %d = %x, want %x", j, i, hashes[j], wantHashes[i][j])
+			}
+		}
+		i++
+	}
+	if i != len(wantHashes) {
+		t.Fatalf("expected %d k-mers from Iter, got %d", len(wantHashes), i)
+	}
+}
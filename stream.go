@@ -0,0 +1,197 @@
+package nthash
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Format describes the framing of the sequence data supplied to a StreamHasher,
+// so that it knows which lines are sequence and which are metadata to be skipped.
+type Format int
+
+const (
+	// FormatPlain treats the entire input as a single, uninterrupted sequence.
+	// Newlines are stripped but otherwise every byte is treated as sequence.
+	FormatPlain Format = iota
+
+	// FormatFASTA skips header lines (starting with '>') and treats every other
+	// line as sequence. A header line starts a new record and resets the rolling
+	// hash so that k-mers never span two records.
+	FormatFASTA
+
+	// FormatFASTQ cycles through the four FASTQ lines per record (header,
+	// sequence, '+' separator, quality) and only hashes the sequence line. A
+	// header line starts a new record and resets the rolling hash.
+	FormatFASTQ
+)
+
+// StreamOpt configures a StreamHasher at construction time.
+type StreamOpt func(*StreamHasher)
+
+// WithFormat sets the input framing used to locate sequence lines within the
+// stream. The default, if no StreamOpt is provided, is FormatPlain.
+func WithFormat(format Format) StreamOpt {
+	return func(sh *StreamHasher) {
+		sh.format = format
+	}
+}
+
+// StreamHasher computes rolling ntHash values for the k-mers in an io.Reader,
+// without requiring the whole sequence to be buffered in memory. It is the
+// streaming counterpart to NTHi, intended for large FASTA/FASTQ input.
+type StreamHasher struct {
+	scanner *bufio.Scanner
+	k       uint
+	format  Format
+
+	// window holds the most recent k bases seen for the current record
+	window []byte
+
+	// fh/rh are the current forward/reverse rolling hash values
+	fh uint64
+	rh uint64
+
+	// recordID is the index of the record currently being hashed, and
+	// seqOffset is the k-mer offset within that record
+	recordID  int
+	seqOffset int
+
+	// fastqLine tracks our position (0..3) within the current 4-line FASTQ record
+	fastqLine int
+
+	// pending holds bytes from the current sequence line not yet consumed
+	pending []byte
+}
+
+// NewStreamHasher is the constructor function for StreamHasher.
+// r is the source of sequence data, and k is the k-mer size to use.
+func NewStreamHasher(r io.Reader, k uint, opts ...StreamOpt) (*StreamHasher, error) {
+	if k == 0 {
+		return nil, fmt.Errorf("k size must be greater than zero")
+	}
+	if k > maxK {
+		return nil, fmt.Errorf("k size is greater than the maximum allowed k size (%d vs %d)", k, maxK)
+	}
+
+	sh := &StreamHasher{
+		scanner:  bufio.NewScanner(r),
+		k:        k,
+		window:   make([]byte, 0, k),
+		recordID: -1,
+	}
+	for _, opt := range opts {
+		opt(sh)
+	}
+	sh.scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024*1024)
+	return sh, nil
+}
+
+// Next returns the next ntHash value from the stream, along with the record
+// it came from and its k-mer offset within that record. ok is false once the
+// stream is exhausted.
+// canonical is set true to return the canonical k-mer hash, otherwise the
+// forward hash is returned.
+func (sh *StreamHasher) Next(canonical bool) (uint64, int, int, bool) {
+	for {
+		base, found := sh.nextBase()
+		if !found {
+			return 0, 0, 0, false
+		}
+
+		if uint(len(sh.window)) < sh.k {
+			sh.window = append(sh.window, base)
+			if uint(len(sh.window)) == sh.k {
+				sh.fh = ntf64(sh.window, 0, sh.k)
+				sh.rh = ntr64(sh.window, 0, sh.k)
+				sh.seqOffset = 0
+				return sh.currentHash(canonical), sh.recordID, sh.seqOffset, true
+			}
+			continue
+		}
+
+		prevBase := sh.window[0]
+		endBase := base
+		// alg 3. of ntHash paper
+		sh.fh = roL(sh.fh, 1)
+		sh.fh ^= roL(seedTab[prevBase], sh.k)
+		sh.fh ^= seedTab[endBase]
+		sh.rh = roR(sh.rh, 1)
+		sh.rh ^= roR(seedTab[prevBase&offset], 1)
+		sh.rh ^= roL(seedTab[endBase&offset], sh.k-1)
+
+		copy(sh.window, sh.window[1:])
+		sh.window[sh.k-1] = base
+		sh.seqOffset++
+
+		return sh.currentHash(canonical), sh.recordID, sh.seqOffset, true
+	}
+}
+
+// currentHash returns the canonical or forward hash currently held by the
+// rolling state.
+func (sh *StreamHasher) currentHash(canonical bool) uint64 {
+	if canonical {
+		if sh.rh < sh.fh {
+			return sh.rh
+		}
+		return sh.fh
+	}
+	return sh.fh
+}
+
+// resetWindow clears the rolling hash state, ready to start a new record.
+func (sh *StreamHasher) resetWindow() {
+	sh.window = sh.window[:0]
+	sh.seqOffset = 0
+}
+
+// nextBase returns the next sequence base from the stream, skipping any
+// header/quality lines according to the configured Format. It returns
+// found=false once the underlying reader is exhausted.
+func (sh *StreamHasher) nextBase() (byte, bool) {
+	for {
+		if len(sh.pending) > 0 {
+			b := sh.pending[0]
+			sh.pending = sh.pending[1:]
+			return b, true
+		}
+		if !sh.scanner.Scan() {
+			return 0, false
+		}
+		line := bytes.TrimRight(sh.scanner.Bytes(), "\r\n")
+
+		switch sh.format {
+		case FormatFASTA:
+			if len(line) > 0 && line[0] == '>' {
+				sh.recordID++
+				sh.resetWindow()
+				continue
+			}
+			sh.pending = line
+		case FormatFASTQ:
+			switch sh.fastqLine {
+			case 0: // header
+				sh.recordID++
+				sh.resetWindow()
+				sh.fastqLine = 1
+				continue
+			case 1: // sequence
+				sh.fastqLine = 2
+				sh.pending = line
+			case 2: // '+' separator
+				sh.fastqLine = 3
+				continue
+			case 3: // quality
+				sh.fastqLine = 0
+				continue
+			}
+		default: // FormatPlain
+			if sh.recordID < 0 {
+				sh.recordID = 0
+			}
+			sh.pending = line
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package nthash
+
+import (
+	"strings"
+	"testing"
+)
+
+// test the StreamHasher constructor
+func TestNewStreamHasher(t *testing.T) {
+	if _, err := NewStreamHasher(strings.NewReader(""), 0); err == nil {
+		t.Fatal("should trigger k==0 error")
+	}
+	if _, err := NewStreamHasher(strings.NewReader(string(kmer2)), 3); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// test that StreamHasher agrees with NTHi for a single plain sequence
+func TestStreamHasherPlain(t *testing.T) {
+	sh, err := NewStreamHasher(strings.NewReader(string(kmer2)), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nthi, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for hash, _ := range nthi.Iter(true) {
+		h, recordID, _, ok := sh.Next(true)
+		if !ok {
+			t.Fatal("stream ended early")
+		}
+		if recordID != 0 {
+			t.Fatal("unexpected record ID for a single-record plain stream")
+		}
+		if h != hash {
+			t.Fatalf("stream hash %x did not match iterator hash %x", h, hash)
+		}
+	}
+	if _, _, _, ok := sh.Next(true); ok {
+		t.Fatal("expected stream to be exhausted")
+	}
+}
+
+// test that FASTA headers are skipped and reset k-mers across records
+func TestStreamHasherFASTA(t *testing.T) {
+	fasta := ">record1\n" + string(kmer2) + "\n>record2\n" + string(kmer2) + "\n"
+	sh, err := NewStreamHasher(strings.NewReader(fasta), 3, WithFormat(FormatFASTA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seenRecords []int
+	for {
+		_, recordID, offset, ok := sh.Next(true)
+		if !ok {
+			break
+		}
+		if len(seenRecords) == 0 || seenRecords[len(seenRecords)-1] != recordID {
+			seenRecords = append(seenRecords, recordID)
+			if offset != 0 {
+				t.Fatal("first k-mer of a new record should have offset 0")
+			}
+		}
+	}
+	if len(seenRecords) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(seenRecords))
+	}
+}
+
+// test that FASTQ quality/plus lines are skipped
+func TestStreamHasherFASTQ(t *testing.T) {
+	fastq := "@read1\n" + string(kmer2) + "\n+\nIII\n"
+	sh, err := NewStreamHasher(strings.NewReader(fastq), 3, WithFormat(FormatFASTQ))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nthi, err := NewHasher(&kmer2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := 0
+	for hash, _ := range nthi.Iter(true) {
+		h, _, _, ok := sh.Next(true)
+		if !ok {
+			t.Fatal("stream ended early")
+		}
+		if h != hash {
+			t.Fatalf("stream hash %x did not match iterator hash %x", h, hash)
+		}
+		counter++
+	}
+	if counter != 3 {
+		t.Fatal("wrong number of k-mers")
+	}
+}